@@ -1,6 +1,7 @@
 package jsonpath
 
 import (
+	"bytes"
 	"encoding/json"
 	"io"
 )
@@ -14,6 +15,10 @@ type Decoder struct {
 
 	path    JsonPath
 	context jsonContext
+
+	dupKeys       bool
+	keyStack      []map[string]struct{}
+	unknownFields []fieldCheck
 }
 
 // NewDecoder creates a new instance of the extended JSON Decoder.
@@ -62,16 +67,58 @@ func (w *Decoder) SeekTo(path ...interface{}) (bool, error) {
 
 // Decode reads the next JSON-encoded value from its input and stores it in the value pointed to by v. This is
 // equivalent to encoding/json.Decode().
+//
+// If DisallowDuplicateKeys or DisallowUnknownFieldsAt are in effect, the value is first decoded into a
+// json.RawMessage and walked token by token to enforce them - encoding/json.Decoder's own Decode never
+// calls back into this Decoder's Token, so without this the checks would only ever run for values read
+// via Token/Scan.
 func (d *Decoder) Decode(v interface{}) error {
+	d.advance()
+
+	if !d.dupKeys && len(d.unknownFields) == 0 {
+		if err := d.Decoder.Decode(v); err != nil {
+			return d.wrapErr(err)
+		}
+		return nil
+	}
+
+	var raw json.RawMessage
+	if err := d.Decoder.Decode(&raw); err != nil {
+		return d.wrapErr(err)
+	}
+	if err := d.checkStrictRaw(raw); err != nil {
+		return d.wrapErr(err)
+	}
+	if err := json.Unmarshal(raw, v); err != nil {
+		return d.wrapErr(err)
+	}
+	return nil
+}
+
+// checkStrictRaw walks raw token by token purely to run the strict-mode checks enabled on d; the tokens
+// themselves are discarded, since the caller unmarshals raw into the real target separately.
+func (d *Decoder) checkStrictRaw(raw json.RawMessage) error {
+	sub := d.subDecoder(d.Path(), raw)
+	for {
+		if _, err := sub.Token(); err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+	}
+}
+
+// advance performs the same path/context bookkeeping Decode does before reading a value, leaving objKey
+// context after an object value or moving to the next array index. It is also used by fireNextElement,
+// which reads the next array element straight from the embedded json.Decoder and so bypasses Decode.
+func (d *Decoder) advance() {
 	switch d.context {
 	case objValue:
 		d.context = objKey
-		break
 	case arrValue:
 		d.path.incTop()
-		break
 	}
-	return d.Decoder.Decode(v)
 }
 
 // Path returns a slice of string and/or int values representing the path from the root of the JSON object to the
@@ -88,7 +135,10 @@ func (d *Decoder) Path() JsonPath {
 func (d *Decoder) Token() (json.Token, error) {
 	t, err := d.Decoder.Token()
 	if err != nil {
-		return t, err
+		if err == io.EOF {
+			return t, err
+		}
+		return t, d.wrapErr(err)
 	}
 
 	if t == nil {
@@ -112,10 +162,12 @@ func (d *Decoder) Token() (json.Token, error) {
 			}
 			d.path.push("")
 			d.context = objKey
+			d.keyStack = append(d.keyStack, newKeySet(d.dupKeys))
 			break
 		case json.Delim('}'):
 			d.path.pop()
 			d.context = d.path.inferContext()
+			d.keyStack = d.keyStack[:len(d.keyStack)-1]
 			break
 		case json.Delim('['):
 			if d.context == arrValue {
@@ -144,6 +196,9 @@ func (d *Decoder) Token() (json.Token, error) {
 		case objKey:
 			d.path.nameTop(t)
 			d.context = objValue
+			if err := d.checkStrict(t); err != nil {
+				return nil, d.wrapErr(err)
+			}
 			return KeyString(t), err
 		case objValue:
 			d.context = objKey
@@ -156,6 +211,144 @@ func (d *Decoder) Token() (json.Token, error) {
 	return t, err
 }
 
+// subDecoder returns a fresh Decoder over the already-decoded raw bytes of a value found at path, for use
+// when that value can no longer be read from the stream: by Scan, to hand it to each firing Action, and
+// by Decode, to walk it for the strict-mode checks. Path() on the returned Decoder
+// reports path, so an Action can't tell it apart from one operating directly on the stream; it inherits
+// the parent's strict-mode settings so they keep applying within the re-decoded value.
+func (d *Decoder) subDecoder(path JsonPath, raw json.RawMessage) *Decoder {
+	sub := NewDecoder(bytes.NewReader(raw))
+	sub.path = append(JsonPath{}, path...)
+	sub.dupKeys = d.dupKeys
+	sub.unknownFields = d.unknownFields
+	return sub
+}
+
+// tokenClass returns a json.Token of the same dynamic type Token() would have produced for raw's value,
+// for Predicate matching against a value that was decoded directly (via Decode) rather than read token by
+// token. Since every Predicate in this package only inspects the token's type, the value itself is a
+// placeholder.
+func tokenClass(raw json.RawMessage) json.Token {
+	trimmed := bytes.TrimSpace(raw)
+	if len(trimmed) == 0 {
+		return nil
+	}
+	switch trimmed[0] {
+	case '"':
+		return ""
+	case '{':
+		return json.Delim('{')
+	case '[':
+		return json.Delim('[')
+	case 't', 'f':
+		return true
+	case 'n':
+		return nil
+	default:
+		return float64(0)
+	}
+}
+
+// scalarRaw reports whether tok is a scalar JSON value that Token() has already read in full (as opposed
+// to a KeyString, whose value is yet to come, or a json.Delim, only the opening of whose container has
+// been read), and if so returns its raw JSON encoding. Such a token can no longer be obtained by calling
+// Decode on the stream: encoding/json.Decoder would instead decode whatever value follows it.
+func scalarRaw(tok json.Token) (json.RawMessage, bool) {
+	switch tok.(type) {
+	case float64, json.Number, bool, string:
+		b, err := json.Marshal(tok)
+		if err != nil {
+			return nil, false
+		}
+		return b, true
+	case nil:
+		return json.RawMessage("null"), true
+	default:
+		return nil, false
+	}
+}
+
+// fire looks up the nodes matching relPath and, among those whose predicate (if any) accepts tok, invokes
+// their actions. If tok is a scalar, Token() has already consumed it in full, so it is re-encoded from tok
+// itself and handed to each firing Action via a subDecoder. Otherwise a single match runs directly against
+// d, consuming the value straight from the stream; two or more matches are only possible by decoding the
+// value once into a json.RawMessage and giving each Action its own Decoder over those bytes, since
+// encoding/json.Decoder cannot rewind.
+func (d *Decoder) fire(ext *PathActions, relPath JsonPath, tok json.Token) (bool, error) {
+	var nodes []*node
+	ext.node.match(relPath, &nodes)
+	var firing []*node
+	for _, n := range nodes {
+		if n.predicate != nil && !n.predicate(tok) {
+			continue
+		}
+		firing = append(firing, n)
+	}
+	if len(firing) == 0 {
+		return false, nil
+	}
+
+	if raw, ok := scalarRaw(tok); ok {
+		// Token() already advanced the path to this element's own index when it read tok; fireNextElement
+		// is solely responsible for advancing to the *next* element, so no advance happens here.
+		path := d.Path()
+		for _, n := range firing {
+			n.action(d.subDecoder(path, raw))
+		}
+		return true, nil
+	}
+
+	if len(firing) == 1 {
+		firing[0].action(d)
+		return true, nil
+	}
+
+	leafPath := d.Path()
+	var raw json.RawMessage
+	if err := d.Decode(&raw); err != nil {
+		return false, err
+	}
+	for _, n := range firing {
+		n.action(d.subDecoder(leafPath, raw))
+	}
+	return true, nil
+}
+
+// fireNextElement handles the second and later elements of an array matched by a Wildcard or Slice
+// pattern. Unlike the first element, whose token was just read by Token(), there is no token to inspect
+// without decoding the element, so it is always decoded once into a json.RawMessage up front: both to
+// classify it for Predicate matching and, if it matches, to hand to the firing Actions.
+//
+// fireNextElement performs the index advance for this element itself, via the same advance() Decode
+// uses, then reads the element's raw bytes directly from the embedded json.Decoder rather than through
+// this Decoder's own Decode - which would advance a second time and permanently drift the reported path
+// for the rest of the array.
+func (d *Decoder) fireNextElement(ext *PathActions, rootPath JsonPath) (bool, error) {
+	d.advance()
+	leafPath := d.Path()
+	relPath := leafPath[len(rootPath):]
+
+	var raw json.RawMessage
+	if err := d.Decoder.Decode(&raw); err != nil {
+		return false, d.wrapErr(err)
+	}
+	tok := tokenClass(raw)
+
+	var nodes []*node
+	ext.node.match(relPath, &nodes)
+	var firing []*node
+	for _, n := range nodes {
+		if n.predicate != nil && !n.predicate(tok) {
+			continue
+		}
+		firing = append(firing, n)
+	}
+	for _, n := range firing {
+		n.action(d.subDecoder(leafPath, raw))
+	}
+	return len(firing) > 0, nil
+}
+
 func (d *Decoder) Scan(ext *PathActions) (bool, error) {
 
 	matched := false
@@ -165,30 +358,32 @@ func (d *Decoder) Scan(ext *PathActions) (bool, error) {
 	}
 
 	for {
-		_, err := d.Token()
+		tok, err := d.Token()
 		if err != nil {
 			return matched, err
 		}
 
-	match:
 		path := d.Path()
-		relPath := JsonPath{}
-
-		// fmt.Printf("rootPath: %v path: %v rel: %v\n", rootPath, path, relPath)
-
-		if len(path) > len(rootPath) {
-			relPath = path[len(rootPath):]
-		} else {
+		if len(path) <= len(rootPath) {
 			return matched, nil
 		}
+		relPath := path[len(rootPath):]
+
+		fired, err := d.fire(ext, relPath, tok)
+		if err != nil {
+			return matched, err
+		}
+		if fired {
+			matched = true
+		}
 
-		if node, ok := ext.node.match(relPath); ok {
-			if node.action != nil {
+		for fired && d.context == arrValue && d.Decoder.More() {
+			fired, err = d.fireNextElement(ext, rootPath)
+			if err != nil {
+				return matched, err
+			}
+			if fired {
 				matched = true
-				node.action(d)
-				if d.context == arrValue && d.Decoder.More() {
-					goto match
-				}
 			}
 		}
 	}
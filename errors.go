@@ -0,0 +1,42 @@
+package jsonpath
+
+import (
+	"fmt"
+	"io"
+)
+
+// DecodeError wraps an error returned while decoding, adding the Decoder's path and byte offset at the
+// point of failure so callers can report pinpoint locations such as
+// "at /spec/containers/2/image (offset 18423): expected string, got number".
+type DecodeError struct {
+	Path    JsonPath
+	Pointer JSONPointer
+	Offset  int64
+	Err     error
+}
+
+func (e *DecodeError) Error() string {
+	return fmt.Sprintf("jsonpath: at %s (offset %d): %v", e.Pointer, e.Offset, e.Err)
+}
+
+// Unwrap allows errors.Is/errors.As to see through a DecodeError to the underlying error.
+func (e *DecodeError) Unwrap() error { return e.Err }
+
+// wrapErr annotates a non-EOF error with the Decoder's current path and offset. io.EOF and an
+// already-wrapped *DecodeError (e.g. one returned by the sub-decoder Decode walks for its strict-mode
+// checks) are returned unchanged.
+func (d *Decoder) wrapErr(err error) error {
+	if err == nil || err == io.EOF {
+		return err
+	}
+	if _, ok := err.(*DecodeError); ok {
+		return err
+	}
+	path := d.Path()
+	return &DecodeError{
+		Path:    path,
+		Pointer: path.Pointer(),
+		Offset:  d.InputOffset(),
+		Err:     err,
+	}
+}
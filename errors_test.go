@@ -0,0 +1,43 @@
+package jsonpath
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestDecodeErrorWrapsUnderlyingError(t *testing.T) {
+	d := NewDecoder(strings.NewReader(`{"a":"not a number"}`))
+
+	if _, err := d.Token(); err != nil { // {
+		t.Fatalf("Token: %v", err)
+	}
+	if _, err := d.Token(); err != nil { // "a"
+		t.Fatalf("Token: %v", err)
+	}
+
+	var v int
+	err := d.Decode(&v)
+	if err == nil {
+		t.Fatal("Decode: expected type-mismatch error, got nil")
+	}
+
+	var decErr *DecodeError
+	if !errors.As(err, &decErr) {
+		t.Fatalf("Decode error = %v (%T), want a *DecodeError", err, err)
+	}
+	if got, want := decErr.Pointer, JSONPointer("/a"); got != want {
+		t.Fatalf("decErr.Pointer = %q, want %q", got, want)
+	}
+	if decErr.Unwrap() == nil {
+		t.Fatal("decErr.Unwrap() = nil, want the underlying json error")
+	}
+}
+
+func TestDecodeErrorPassesThroughEOF(t *testing.T) {
+	d := NewDecoder(strings.NewReader(``))
+	if _, err := d.Token(); err != io.EOF {
+		t.Fatalf("Token() error = %v, want io.EOF", err)
+	}
+}
@@ -0,0 +1,226 @@
+package jsonpath
+
+import (
+	"encoding/json"
+	"reflect"
+	"strconv"
+)
+
+// Action is a function that is called when a PathActions-registered path is encountered by Decoder.Scan.
+type Action func(*Decoder)
+
+// Predicate filters whether a matched leaf's Action fires, based on the JSON type of the value found
+// there. It is added as the final element of a PathActions.Add path, equivalent to a gjson/JSONPath leaf
+// filter such as "?type=string". See StringValue, NumberValue, BoolValue, ObjectValue, ArrayValue and
+// NullValue.
+type Predicate func(json.Token) bool
+
+// StringValue is a Predicate matching string values.
+var StringValue Predicate = func(t json.Token) bool { _, ok := t.(string); return ok }
+
+// NumberValue is a Predicate matching numeric values.
+var NumberValue Predicate = func(t json.Token) bool {
+	switch t.(type) {
+	case float64, json.Number:
+		return true
+	}
+	return false
+}
+
+// BoolValue is a Predicate matching boolean values.
+var BoolValue Predicate = func(t json.Token) bool { _, ok := t.(bool); return ok }
+
+// ObjectValue is a Predicate matching object values.
+var ObjectValue Predicate = func(t json.Token) bool { d, ok := t.(json.Delim); return ok && d == '{' }
+
+// ArrayValue is a Predicate matching array values.
+var ArrayValue Predicate = func(t json.Token) bool { d, ok := t.(json.Delim); return ok && d == '[' }
+
+// NullValue is a Predicate matching the JSON null value.
+var NullValue Predicate = func(t json.Token) bool { return t == nil }
+
+var predicateType = reflect.TypeOf(Predicate(nil))
+
+// asPredicate reports whether v is usable as a Predicate: either already that named type, or any other
+// func value with the same func(json.Token) bool signature, such as an inline func literal passed as the
+// trailing argument to Add. Without this, such a literal would fall through to being treated as a path
+// segment and panic when used as a map key, since func values aren't comparable.
+func asPredicate(v interface{}) (Predicate, bool) {
+	if pr, ok := v.(Predicate); ok {
+		return pr, true
+	}
+	rv := reflect.ValueOf(v)
+	if rv.IsValid() && rv.Kind() == reflect.Func && rv.Type().ConvertibleTo(predicateType) {
+		return rv.Convert(predicateType).Interface().(Predicate), true
+	}
+	return nil, false
+}
+
+type wildcard struct{}
+
+// Wildcard, used as a PathActions.Add path segment, matches any single object key or array index at
+// that level, e.g. Add(action, "items", Wildcard, "id").
+var Wildcard interface{} = wildcard{}
+
+type recursiveDescent struct{}
+
+// RecursiveDescent, used as a PathActions.Add path segment, matches any depth (zero or more levels),
+// e.g. Add(action, "items", RecursiveDescent, "id") fires for "id" at any depth under "items".
+var RecursiveDescent interface{} = recursiveDescent{}
+
+// SliceOpenEnd, used as Slice.To, matches through the last element of the array.
+const SliceOpenEnd = -1
+
+// Slice, used as a PathActions.Add path segment, matches array indices in the half-open range
+// [From, To), like a Go slice expression. A To of SliceOpenEnd matches through the end of the array.
+type Slice struct {
+	From, To int
+}
+
+func (s Slice) contains(i int) bool {
+	return i >= s.From && (s.To == SliceOpenEnd || i < s.To)
+}
+
+// PathActions is a trie of path patterns (object keys, array indices, wildcards, recursive descent and
+// slice ranges) used by Decoder.Scan to invoke one or more Actions when the decoder's current path
+// matches registered patterns.
+type PathActions struct {
+	node node
+}
+
+type sliceChild struct {
+	Slice
+	node *node
+}
+
+type node struct {
+	child     map[interface{}]*node
+	wildcard  *node
+	recursive *node
+	slices    []sliceChild
+	predicate Predicate
+	action    Action
+}
+
+// Add registers an Action to be called when Scan encounters a path matching the given pattern. Each
+// element of path is a string or int (an exact object key or array index, as with Decoder.SeekTo),
+// Wildcard, RecursiveDescent, or a Slice. A JSONPointer anywhere in path is expanded in place into its
+// constituent reference tokens, converting digit tokens to array indices, so callers that already carry
+// a pointer (e.g. from an upstream error) can register against it directly. A trailing Predicate
+// restricts the Action to leaves whose value matches it, e.g. Add(action, "items", Wildcard, StringValue).
+func (p *PathActions) Add(action Action, path ...interface{}) {
+	path = expandPointers(path)
+
+	var pred Predicate
+	if len(path) > 0 {
+		if pr, ok := asPredicate(path[len(path)-1]); ok {
+			pred = pr
+			path = path[:len(path)-1]
+		}
+	}
+
+	n := &p.node
+	for _, s := range path {
+		switch s := s.(type) {
+		case wildcard:
+			if n.wildcard == nil {
+				n.wildcard = &node{}
+			}
+			n = n.wildcard
+		case recursiveDescent:
+			if n.recursive == nil {
+				n.recursive = &node{}
+			}
+			n = n.recursive
+		case Slice:
+			n = n.sliceChild(s)
+		default:
+			if n.child == nil {
+				n.child = make(map[interface{}]*node)
+			}
+			c, ok := n.child[s]
+			if !ok {
+				c = &node{}
+				n.child[s] = c
+			}
+			n = c
+		}
+	}
+	n.action = action
+	n.predicate = pred
+}
+
+func (n *node) sliceChild(s Slice) *node {
+	for _, sc := range n.slices {
+		if sc.Slice == s {
+			return sc.node
+		}
+	}
+	c := &node{}
+	n.slices = append(n.slices, sliceChild{Slice: s, node: c})
+	return c
+}
+
+// expandPointers rewrites path, replacing any JSONPointer element with its reference tokens, converting
+// tokens that look like array indices (no leading zeros) to int.
+func expandPointers(path []interface{}) []interface{} {
+	out := make([]interface{}, 0, len(path))
+	for _, s := range path {
+		ptr, ok := s.(JSONPointer)
+		if !ok {
+			out = append(out, s)
+			continue
+		}
+		for _, tok := range ptr.tokens() {
+			if isArrayIndexToken(tok) {
+				n, _ := strconv.Atoi(tok)
+				out = append(out, n)
+			} else {
+				out = append(out, tok)
+			}
+		}
+	}
+	return out
+}
+
+// match collects every leaf node reachable from n by walking path, following literal, wildcard,
+// recursive-descent and slice branches as applicable. Multiple patterns can match the same path, so all
+// matching leaves with a registered action are returned.
+func (n *node) match(path JsonPath, out *[]*node) {
+	if len(path) == 0 {
+		if n.action != nil {
+			*out = append(*out, n)
+		}
+		return
+	}
+
+	seg := path[0]
+	rest := path[1:]
+
+	// -1 marks an array that has been entered but whose first element hasn't been read yet (see
+	// Decoder.Token); it is a bookkeeping placeholder, not a real index, and must never match.
+	if idx, ok := seg.(int); ok && idx < 0 {
+		return
+	}
+
+	if n.child != nil {
+		if c, ok := n.child[seg]; ok {
+			c.match(rest, out)
+		}
+	}
+	if n.wildcard != nil {
+		n.wildcard.match(rest, out)
+	}
+	if idx, ok := seg.(int); ok {
+		for _, sc := range n.slices {
+			if sc.contains(idx) {
+				sc.node.match(rest, out)
+			}
+		}
+	}
+	if n.recursive != nil {
+		for i := 0; i <= len(path); i++ {
+			n.recursive.match(path[i:], out)
+		}
+	}
+}
@@ -0,0 +1,242 @@
+package jsonpath
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestPathActionsWildcardAndLiteral(t *testing.T) {
+	const doc = `{"items":[{"id":"a","name":"alpha"},{"id":"b","name":"beta"}]}`
+
+	var ids []string
+	var names []string
+
+	var actions PathActions
+	actions.Add(func(d *Decoder) {
+		var id string
+		if err := d.Decode(&id); err != nil {
+			t.Fatalf("decode id: %v", err)
+		}
+		ids = append(ids, id)
+	}, "items", Wildcard, "id")
+	actions.Add(func(d *Decoder) {
+		var name string
+		if err := d.Decode(&name); err != nil {
+			t.Fatalf("decode name: %v", err)
+		}
+		names = append(names, name)
+	}, "items", Wildcard, "name")
+
+	d := NewDecoder(strings.NewReader(doc))
+	if _, err := d.Scan(&actions); err != nil {
+		t.Fatalf("Scan: %v", err)
+	}
+
+	if got, want := strings.Join(ids, ","), "a,b"; got != want {
+		t.Fatalf("ids = %q, want %q", got, want)
+	}
+	if got, want := strings.Join(names, ","), "alpha,beta"; got != want {
+		t.Fatalf("names = %q, want %q", got, want)
+	}
+}
+
+func TestPathActionsMultipleHandlersSamePath(t *testing.T) {
+	const doc = `{"items":[42]}`
+
+	var first, second int
+
+	// Two distinct patterns (a literal index and a wildcard) that both match items[0]: Scan must
+	// give each handler its own decodable copy of the value rather than letting the first Decode
+	// consume the only one.
+	var actions PathActions
+	actions.Add(func(d *Decoder) {
+		if err := d.Decode(&first); err != nil {
+			t.Fatalf("first handler decode: %v", err)
+		}
+	}, "items", 0)
+	actions.Add(func(d *Decoder) {
+		if err := d.Decode(&second); err != nil {
+			t.Fatalf("second handler decode: %v", err)
+		}
+	}, "items", Wildcard)
+
+	d := NewDecoder(strings.NewReader(doc))
+	if _, err := d.Scan(&actions); err != nil {
+		t.Fatalf("Scan: %v", err)
+	}
+
+	if first != 42 {
+		t.Fatalf("first = %v, want 42", first)
+	}
+	if second != 42 {
+		t.Fatalf("second = %v, want 42", second)
+	}
+}
+
+func TestPathActionsRecursiveDescent(t *testing.T) {
+	const doc = `{"a":{"v":1},"b":{"c":{"v":2}}}`
+
+	var vs []float64
+	var actions PathActions
+	actions.Add(func(d *Decoder) {
+		var v float64
+		if err := d.Decode(&v); err != nil {
+			t.Fatalf("decode v: %v", err)
+		}
+		vs = append(vs, v)
+	}, RecursiveDescent, "v")
+
+	d := NewDecoder(strings.NewReader(doc))
+	if _, err := d.Scan(&actions); err != nil {
+		t.Fatalf("Scan: %v", err)
+	}
+	if len(vs) != 2 || vs[0] != 1 || vs[1] != 2 {
+		t.Fatalf("vs = %v, want [1 2]", vs)
+	}
+}
+
+func TestPathActionsPredicate(t *testing.T) {
+	const doc = `{"items":[1,"two",3]}`
+
+	var strs []string
+	var actions PathActions
+	actions.Add(func(d *Decoder) {
+		var s string
+		if err := d.Decode(&s); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		strs = append(strs, s)
+	}, "items", Wildcard, StringValue)
+
+	d := NewDecoder(strings.NewReader(doc))
+	if _, err := d.Scan(&actions); err != nil {
+		t.Fatalf("Scan: %v", err)
+	}
+	if len(strs) != 1 || strs[0] != "two" {
+		t.Fatalf("strs = %v, want [two]", strs)
+	}
+}
+
+func TestPathActionsSlice(t *testing.T) {
+	const doc = `{"items":[0,1,2,3,4]}`
+
+	var seen []int
+	var actions PathActions
+	actions.Add(func(d *Decoder) {
+		var n int
+		if err := d.Decode(&n); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		seen = append(seen, n)
+	}, "items", Slice{From: 1, To: 3})
+
+	d := NewDecoder(strings.NewReader(doc))
+	if _, err := d.Scan(&actions); err != nil {
+		t.Fatalf("Scan: %v", err)
+	}
+	if len(seen) != 2 || seen[0] != 1 || seen[1] != 2 {
+		t.Fatalf("seen = %v, want [1 2]", seen)
+	}
+}
+
+func TestPathActionsMixedPatternsOverOneArray(t *testing.T) {
+	const doc = `["a",123,"exact-index-2","d"]`
+
+	var strs []string
+	var exact string
+
+	// A Wildcard+predicate pattern and a literal-index pattern over the same array: once the wildcard
+	// match at index 0 falls through to fireNextElement for index 1 (a non-match), the literal-index
+	// pattern must still fire correctly at index 2 rather than being permanently shifted off by one.
+	var actions PathActions
+	actions.Add(func(d *Decoder) {
+		var s string
+		if err := d.Decode(&s); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		strs = append(strs, s)
+	}, Wildcard, StringValue)
+	actions.Add(func(d *Decoder) {
+		if err := d.Decode(&exact); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+	}, 2)
+
+	d := NewDecoder(strings.NewReader(doc))
+	if _, err := d.Scan(&actions); err != nil {
+		t.Fatalf("Scan: %v", err)
+	}
+
+	if got, want := strings.Join(strs, ","), "a,exact-index-2,d"; got != want {
+		t.Fatalf("strs = %q, want %q", got, want)
+	}
+	if exact != "exact-index-2" {
+		t.Fatalf("exact = %q, want %q", exact, "exact-index-2")
+	}
+}
+
+func TestExpandPointers(t *testing.T) {
+	ptr, err := ParsePointer("/a/0/b")
+	if err != nil {
+		t.Fatalf("ParsePointer: %v", err)
+	}
+	out := expandPointers([]interface{}{ptr})
+	want := []interface{}{"a", 0, "b"}
+	if len(out) != len(want) {
+		t.Fatalf("expandPointers = %v, want %v", out, want)
+	}
+	for i := range want {
+		if out[i] != want[i] {
+			t.Fatalf("expandPointers[%d] = %v, want %v", i, out[i], want[i])
+		}
+	}
+}
+
+func TestPredicates(t *testing.T) {
+	cases := []struct {
+		name string
+		pred Predicate
+		tok  json.Token
+		want bool
+	}{
+		{"StringValue match", StringValue, "s", true},
+		{"StringValue mismatch", StringValue, float64(1), false},
+		{"NumberValue float", NumberValue, float64(1), true},
+		{"BoolValue match", BoolValue, true, true},
+		{"ObjectValue match", ObjectValue, json.Delim('{'), true},
+		{"ArrayValue match", ArrayValue, json.Delim('['), true},
+		{"NullValue match", NullValue, nil, true},
+	}
+	for _, c := range cases {
+		if got := c.pred(c.tok); got != c.want {
+			t.Errorf("%s: got %v, want %v", c.name, got, c.want)
+		}
+	}
+}
+
+func TestAddAcceptsPlainFuncPredicate(t *testing.T) {
+	const doc = `["one",2,"three"]`
+
+	var strs []string
+	var actions PathActions
+	actions.Add(func(d *Decoder) {
+		var s string
+		if err := d.Decode(&s); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		strs = append(strs, s)
+	}, Wildcard, func(t json.Token) bool { // a plain func literal, not the named Predicate type
+		_, ok := t.(string)
+		return ok
+	})
+
+	d := NewDecoder(strings.NewReader(doc))
+	if _, err := d.Scan(&actions); err != nil {
+		t.Fatalf("Scan: %v", err)
+	}
+
+	if got, want := strings.Join(strs, ","), "one,three"; got != want {
+		t.Fatalf("strs = %q, want %q", got, want)
+	}
+}
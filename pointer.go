@@ -0,0 +1,168 @@
+package jsonpath
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"iter"
+	"strconv"
+	"strings"
+)
+
+// JSONPointer is a string in the RFC 6901 JSON Pointer syntax, e.g. "/a/3/v". The empty JSONPointer ("")
+// refers to the whole document.
+type JSONPointer string
+
+// ParsePointer parses s as an RFC 6901 JSON Pointer, returning an error if it is not well-formed. The
+// empty string is a valid pointer referring to the root of the document.
+func ParsePointer(s string) (JSONPointer, error) {
+	if s == "" {
+		return JSONPointer(""), nil
+	}
+	if s[0] != '/' {
+		return "", fmt.Errorf("jsonpath: invalid JSON pointer %q: must be empty or start with '/'", s)
+	}
+	for _, tok := range strings.Split(s[1:], "/") {
+		for i := 0; i < len(tok); i++ {
+			if tok[i] != '~' {
+				continue
+			}
+			if i+1 >= len(tok) || (tok[i+1] != '0' && tok[i+1] != '1') {
+				return "", fmt.Errorf("jsonpath: invalid JSON pointer %q: '~' must be followed by '0' or '1'", s)
+			}
+		}
+	}
+	return JSONPointer(s), nil
+}
+
+// Pointer converts a JsonPath into the equivalent RFC 6901 JSON Pointer.
+func (p JsonPath) Pointer() JSONPointer {
+	var b strings.Builder
+	for _, seg := range p {
+		b.WriteByte('/')
+		switch s := seg.(type) {
+		case string:
+			b.WriteString(escapePointerToken(s))
+		case int:
+			b.WriteString(strconv.Itoa(s))
+		}
+	}
+	return JSONPointer(b.String())
+}
+
+// Tokens ranges over the reference tokens of the pointer, in order, with the "~0"/"~1" escapes decoded.
+// A token is always returned as a string; callers that need to tell an object key from an array index
+// (e.g. Decoder.SeekToPointer) must do so using the context in which the token is applied.
+func (p JSONPointer) Tokens() iter.Seq[any] {
+	return func(yield func(any) bool) {
+		if p == "" {
+			return
+		}
+		for _, tok := range strings.Split(string(p)[1:], "/") {
+			if !yield(unescapePointerToken(tok)) {
+				return
+			}
+		}
+	}
+}
+
+func (p JSONPointer) tokens() []string {
+	if p == "" {
+		return nil
+	}
+	raw := strings.Split(string(p)[1:], "/")
+	toks := make([]string, len(raw))
+	for i, t := range raw {
+		toks[i] = unescapePointerToken(t)
+	}
+	return toks
+}
+
+func escapePointerToken(s string) string {
+	if !strings.ContainsAny(s, "~/") {
+		return s
+	}
+	s = strings.ReplaceAll(s, "~", "~0")
+	s = strings.ReplaceAll(s, "/", "~1")
+	return s
+}
+
+func unescapePointerToken(s string) string {
+	if !strings.Contains(s, "~") {
+		return s
+	}
+	s = strings.ReplaceAll(s, "~1", "/")
+	s = strings.ReplaceAll(s, "~0", "~")
+	return s
+}
+
+// isArrayIndexToken reports whether s is a valid RFC 6901 array index token: "0", or a non-zero digit
+// followed by any number of digits. Leading zeros ("01") are not valid indices.
+func isArrayIndexToken(s string) bool {
+	if s == "0" {
+		return true
+	}
+	if s == "" || s[0] < '1' || s[0] > '9' {
+		return false
+	}
+	for i := 1; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// SeekToPointer causes the Decoder to move forward to the location identified by an RFC 6901 JSON
+// Pointer. It behaves like SeekTo, but resolves each pointer token against the document as it is
+// encountered: a token made up of digits denotes an array index when the current context is an array,
+// and denotes an object key otherwise. As with SeekTo, the decoder only navigates forward.
+//
+// A pointer whose last token is "-" (the reserved "append" position from RFC 6902) cannot be sought and
+// returns an error.
+func (d *Decoder) SeekToPointer(ptr JSONPointer) (bool, error) {
+	toks := ptr.tokens()
+	if len(toks) == 0 {
+		return d.SeekTo()
+	}
+	if toks[len(toks)-1] == "-" {
+		return false, fmt.Errorf("jsonpath: pointer %q ends in the reserved '-' token and cannot be sought", ptr)
+	}
+
+	path := make(JsonPath, 0, len(toks))
+	for _, tok := range toks {
+		t, err := d.Token()
+		if err == io.EOF {
+			return false, nil
+		} else if err != nil {
+			return false, err
+		}
+		switch t {
+		case json.Delim('['), json.Delim('{'):
+			// entered a container; d.context now reflects it
+		default:
+			// a scalar was found where the pointer expects a container to descend into
+			return false, nil
+		}
+
+		if d.context == arrValue {
+			n, err := strconv.Atoi(tok)
+			if err != nil || !isArrayIndexToken(tok) {
+				return false, fmt.Errorf("jsonpath: pointer %q expects an array index but found %q", ptr, tok)
+			}
+			path = append(path, n)
+		} else {
+			path = append(path, tok)
+		}
+
+		// SeekTo mutates the last element of the slice it's given in place, so it must not be
+		// handed the same backing array path will keep appending to on the next iteration.
+		seekPath := make(JsonPath, len(path))
+		copy(seekPath, path)
+		ok, err := d.SeekTo(seekPath...)
+		if !ok || err != nil {
+			return ok, err
+		}
+	}
+	return true, nil
+}
@@ -0,0 +1,70 @@
+package jsonpath
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParsePointer(t *testing.T) {
+	if _, err := ParsePointer(""); err != nil {
+		t.Fatalf("ParsePointer(\"\") error: %v", err)
+	}
+	if _, err := ParsePointer("no-leading-slash"); err == nil {
+		t.Fatal("expected error for pointer missing leading '/'")
+	}
+	if _, err := ParsePointer("/a~2"); err == nil {
+		t.Fatal("expected error for invalid '~' escape")
+	}
+	if _, err := ParsePointer("/a/3/v"); err != nil {
+		t.Fatalf("ParsePointer(\"/a/3/v\") error: %v", err)
+	}
+}
+
+func TestJsonPathPointer(t *testing.T) {
+	p := JsonPath{"a", 3, "v"}
+	if got, want := p.Pointer(), JSONPointer("/a/3/v"); got != want {
+		t.Fatalf("Pointer() = %q, want %q", got, want)
+	}
+
+	p = JsonPath{"a/b", "c~d"}
+	if got, want := p.Pointer(), JSONPointer("/a~1b/c~0d"); got != want {
+		t.Fatalf("Pointer() = %q, want %q", got, want)
+	}
+}
+
+func TestSeekToPointer(t *testing.T) {
+	const doc = `{ "a": [0,"s",12e4,{"b":0,"v":35} ] }`
+
+	ptr, err := ParsePointer("/a/3/v")
+	if err != nil {
+		t.Fatalf("ParsePointer: %v", err)
+	}
+
+	d := NewDecoder(strings.NewReader(doc))
+	ok, err := d.SeekToPointer(ptr)
+	if err != nil {
+		t.Fatalf("SeekToPointer: %v", err)
+	}
+	if !ok {
+		t.Fatal("SeekToPointer: no match found")
+	}
+
+	var v int
+	if err := d.Decode(&v); err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	if v != 35 {
+		t.Fatalf("Decode = %d, want 35", v)
+	}
+}
+
+func TestSeekToPointerReservedAppendToken(t *testing.T) {
+	d := NewDecoder(strings.NewReader(`{"a":[1,2,3]}`))
+	ptr, err := ParsePointer("/a/-")
+	if err != nil {
+		t.Fatalf("ParsePointer: %v", err)
+	}
+	if _, err := d.SeekToPointer(ptr); err == nil {
+		t.Fatal("expected error for pointer ending in reserved '-' token")
+	}
+}
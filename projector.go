@@ -0,0 +1,263 @@
+package jsonpath
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// FieldMask selects the set of fields a Projector keeps when streaming a JSON document, analogous to a
+// google.protobuf.FieldMask / AIP-157 partial response. Each path is either a dotted field path with an
+// optional array selector ("a.b.c", "a.*.id", "items[*].name", "items[2].name") or a JSONPointer.
+// Everything needed to reach a selected path is kept; everything else is dropped.
+type FieldMask struct {
+	root maskNode
+}
+
+type maskNode struct {
+	leaf     bool
+	children map[string]*maskNode
+	wildcard *maskNode
+}
+
+// NewFieldMask builds a FieldMask keeping exactly the given paths.
+func NewFieldMask(paths ...interface{}) (*FieldMask, error) {
+	fm := &FieldMask{}
+	for _, p := range paths {
+		toks, err := maskTokens(p)
+		if err != nil {
+			return nil, err
+		}
+		n := &fm.root
+		for _, t := range toks {
+			if t == "*" {
+				if n.wildcard == nil {
+					n.wildcard = &maskNode{}
+				}
+				n = n.wildcard
+				continue
+			}
+			if n.children == nil {
+				n.children = make(map[string]*maskNode)
+			}
+			c, ok := n.children[t]
+			if !ok {
+				c = &maskNode{}
+				n.children[t] = c
+			}
+			n = c
+		}
+		n.leaf = true
+	}
+	return fm, nil
+}
+
+func maskTokens(p interface{}) ([]string, error) {
+	switch v := p.(type) {
+	case JSONPointer:
+		return v.tokens(), nil
+	case string:
+		return parseMaskPath(v)
+	default:
+		return nil, fmt.Errorf("jsonpath: FieldMask path must be a string or JSONPointer, got %T", p)
+	}
+}
+
+// parseMaskPath splits an AIP-157-style dotted field path, with optional "[n]"/"[*]" array selectors,
+// into its component tokens: "items[*].name" -> ["items", "*", "name"].
+func parseMaskPath(p string) ([]string, error) {
+	var toks []string
+	for _, seg := range strings.Split(p, ".") {
+		field := seg
+		if i := strings.IndexByte(seg, '['); i >= 0 {
+			if seg[len(seg)-1] != ']' {
+				return nil, fmt.Errorf("jsonpath: invalid field mask path %q", p)
+			}
+			field = seg[:i]
+			idx := seg[i+1 : len(seg)-1]
+			if field == "" || idx == "" {
+				return nil, fmt.Errorf("jsonpath: invalid field mask path %q", p)
+			}
+			toks = append(toks, field, idx)
+			continue
+		}
+		if field == "" {
+			return nil, fmt.Errorf("jsonpath: invalid field mask path %q", p)
+		}
+		toks = append(toks, field)
+	}
+	return toks, nil
+}
+
+// frontier is the set of maskNodes a path through the document could still be matching. More than one
+// node can be live at once: e.g. "items.*.name" and "items.2.id" both keep candidates alive under "items".
+type frontier []*maskNode
+
+func (f frontier) leaf() bool {
+	for _, n := range f {
+		if n.leaf {
+			return true
+		}
+	}
+	return false
+}
+
+func (f frontier) step(key string) frontier {
+	var next frontier
+	for _, n := range f {
+		if c, ok := n.children[key]; ok {
+			next = append(next, c)
+		}
+		if n.wildcard != nil {
+			next = append(next, n.wildcard)
+		}
+	}
+	return next
+}
+
+// Projector streams a JSON document, writing only the subtrees selected by a FieldMask, in input order
+// and with valid JSON structure - e.g. so an API server can trim a large response without loading it
+// into memory.
+type Projector struct {
+	d    *Decoder
+	w    *bufio.Writer
+	mask *FieldMask
+}
+
+// NewProjector creates a Projector that reads JSON from r and writes the fields selected by mask to w.
+func NewProjector(r io.Reader, w io.Writer, mask *FieldMask) *Projector {
+	return &Projector{d: NewDecoder(r), w: bufio.NewWriter(w), mask: mask}
+}
+
+// Run streams the projection, returning any error encountered reading or writing.
+func (p *Projector) Run() error {
+	if err := p.projectValue(frontier{&p.mask.root}); err != nil {
+		return err
+	}
+	return p.w.Flush()
+}
+
+// projectValue is called with the Decoder positioned immediately before a value. A fully-matched
+// frontier copies the value's raw bytes; an empty frontier discards it; otherwise its structure is
+// inspected so its children can be filtered in turn.
+func (p *Projector) projectValue(f frontier) error {
+	if f.leaf() {
+		return p.copyRaw()
+	}
+	if len(f) == 0 {
+		return p.skipValue()
+	}
+
+	tok, err := p.d.Token()
+	if err != nil {
+		return err
+	}
+	if d, ok := tok.(json.Delim); ok {
+		switch d {
+		case json.Delim('{'):
+			return p.projectObject(f)
+		case json.Delim('['):
+			return p.projectArray(f)
+		}
+	}
+	// A deeper selection was requested but the document has a scalar here: nothing to keep.
+	return nil
+}
+
+func (p *Projector) projectObject(f frontier) error {
+	if err := p.w.WriteByte('{'); err != nil {
+		return err
+	}
+	first := true
+	for p.d.More() {
+		keyTok, err := p.d.Token()
+		if err != nil {
+			return err
+		}
+		key := string(keyTok.(KeyString))
+
+		child := f.step(key)
+		if len(child) == 0 {
+			if err := p.skipValue(); err != nil {
+				return err
+			}
+			continue
+		}
+		if !first {
+			if err := p.w.WriteByte(','); err != nil {
+				return err
+			}
+		}
+		first = false
+		if err := writeJSONString(p.w, key); err != nil {
+			return err
+		}
+		if err := p.w.WriteByte(':'); err != nil {
+			return err
+		}
+		if err := p.projectValue(child); err != nil {
+			return err
+		}
+	}
+	if _, err := p.d.Token(); err != nil { // consume '}'
+		return err
+	}
+	return p.w.WriteByte('}')
+}
+
+func (p *Projector) projectArray(f frontier) error {
+	if err := p.w.WriteByte('['); err != nil {
+		return err
+	}
+	first := true
+	for idx := 0; p.d.More(); idx++ {
+		child := f.step(strconv.Itoa(idx))
+		if len(child) == 0 {
+			if err := p.skipValue(); err != nil {
+				return err
+			}
+			continue
+		}
+		if !first {
+			if err := p.w.WriteByte(','); err != nil {
+				return err
+			}
+		}
+		first = false
+		if err := p.projectValue(child); err != nil {
+			return err
+		}
+	}
+	if _, err := p.d.Token(); err != nil { // consume ']'
+		return err
+	}
+	return p.w.WriteByte(']')
+}
+
+// copyRaw decodes the value at the Decoder's current position and writes its raw bytes unchanged.
+func (p *Projector) copyRaw() error {
+	var raw json.RawMessage
+	if err := p.d.Decode(&raw); err != nil {
+		return err
+	}
+	_, err := p.w.Write(raw)
+	return err
+}
+
+// skipValue decodes and discards the value at the Decoder's current position.
+func (p *Projector) skipValue() error {
+	var discard json.RawMessage
+	return p.d.Decode(&discard)
+}
+
+func writeJSONString(w *bufio.Writer, s string) error {
+	b, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(b)
+	return err
+}
@@ -0,0 +1,115 @@
+package jsonpath
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestProjectorKeepsSelectedFields(t *testing.T) {
+	const doc = `{"id":1,"name":"alpha","secret":"shh","nested":{"keep":1,"drop":2}}`
+
+	mask, err := NewFieldMask("id", "nested.keep")
+	if err != nil {
+		t.Fatalf("NewFieldMask: %v", err)
+	}
+
+	var out bytes.Buffer
+	p := NewProjector(strings.NewReader(doc), &out, mask)
+	if err := p.Run(); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+
+	want := `{"id":1,"nested":{"keep":1}}`
+	if got := out.String(); got != want {
+		t.Fatalf("projected = %q, want %q", got, want)
+	}
+}
+
+func TestProjectorArrayWildcard(t *testing.T) {
+	const doc = `{"items":[{"id":1,"name":"a"},{"id":2,"name":"b"}]}`
+
+	mask, err := NewFieldMask("items[*].id")
+	if err != nil {
+		t.Fatalf("NewFieldMask: %v", err)
+	}
+
+	var out bytes.Buffer
+	p := NewProjector(strings.NewReader(doc), &out, mask)
+	if err := p.Run(); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+
+	want := `{"items":[{"id":1},{"id":2}]}`
+	if got := out.String(); got != want {
+		t.Fatalf("projected = %q, want %q", got, want)
+	}
+}
+
+func TestProjectorArrayIndex(t *testing.T) {
+	const doc = `{"items":[{"id":1},{"id":2},{"id":3}]}`
+
+	mask, err := NewFieldMask("items[1].id")
+	if err != nil {
+		t.Fatalf("NewFieldMask: %v", err)
+	}
+
+	var out bytes.Buffer
+	p := NewProjector(strings.NewReader(doc), &out, mask)
+	if err := p.Run(); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+
+	want := `{"items":[{"id":2}]}`
+	if got := out.String(); got != want {
+		t.Fatalf("projected = %q, want %q", got, want)
+	}
+}
+
+func TestProjectorJSONPointerPath(t *testing.T) {
+	ptr, err := ParsePointer("/a/b")
+	if err != nil {
+		t.Fatalf("ParsePointer: %v", err)
+	}
+	mask, err := NewFieldMask(ptr)
+	if err != nil {
+		t.Fatalf("NewFieldMask: %v", err)
+	}
+
+	const doc = `{"a":{"b":1,"c":2},"d":3}`
+	var out bytes.Buffer
+	p := NewProjector(strings.NewReader(doc), &out, mask)
+	if err := p.Run(); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+
+	want := `{"a":{"b":1}}`
+	if got := out.String(); got != want {
+		t.Fatalf("projected = %q, want %q", got, want)
+	}
+}
+
+func TestProjectorScalarRoot(t *testing.T) {
+	mask, err := NewFieldMask("a")
+	if err != nil {
+		t.Fatalf("NewFieldMask: %v", err)
+	}
+
+	var out bytes.Buffer
+	p := NewProjector(strings.NewReader(`5`), &out, mask)
+	if err := p.Run(); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if got, want := out.String(), ""; got != want {
+		t.Fatalf("projected = %q, want %q", got, want)
+	}
+}
+
+func TestNewFieldMaskInvalidPath(t *testing.T) {
+	if _, err := NewFieldMask("a["); err == nil {
+		t.Fatal("NewFieldMask: expected error for malformed selector")
+	}
+	if _, err := NewFieldMask(5); err == nil {
+		t.Fatal("NewFieldMask: expected error for non-string, non-JSONPointer path")
+	}
+}
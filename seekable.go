@@ -0,0 +1,300 @@
+package jsonpath
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+type offsetRange struct {
+	Start int64
+	End   int64
+}
+
+// indexNode is one entry of a SeekableDecoder's path index: the byte range of a value, plus its children
+// (object keys or array indices) if it is a container.
+type indexNode struct {
+	Range    offsetRange
+	Children map[interface{}]*indexNode
+}
+
+// SeekableDecoder wraps an io.ReadSeeker and, on first use, builds an index mapping every JsonPath in the
+// document to its byte offset range. Unlike Decoder, whose SeekTo only navigates forward through a
+// stream, SeekableDecoder can move to any previously- or not-yet-visited path, since the index already
+// knows where everything is.
+type SeekableDecoder struct {
+	rs    io.ReadSeeker
+	index *indexNode
+	dec   *Decoder
+}
+
+// NewSeekableDecoder creates a SeekableDecoder over rs. The index is built lazily, on the first call to
+// SeekTo, Slice, ForEach or MarshalIndex.
+func NewSeekableDecoder(rs io.ReadSeeker) *SeekableDecoder {
+	return &SeekableDecoder{rs: rs}
+}
+
+// SeekTo moves the SeekableDecoder to path, forwards or backwards, and returns whether it was found. A
+// subsequent call to Decode reads the value there.
+func (s *SeekableDecoder) SeekTo(path ...interface{}) (bool, error) {
+	if err := s.ensureIndex(); err != nil {
+		return false, err
+	}
+	n, ok := s.lookup(path)
+	if !ok {
+		return false, nil
+	}
+	if err := s.seekDecoder(n.Range.Start); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Decode reads the value at the SeekableDecoder's current position, as last set by SeekTo or ForEach,
+// into v.
+func (s *SeekableDecoder) Decode(v interface{}) error {
+	if s.dec == nil {
+		return fmt.Errorf("jsonpath: SeekableDecoder.Decode called before SeekTo")
+	}
+	return s.dec.Decode(v)
+}
+
+// Slice returns a reader over the raw, unparsed JSON bytes of the subtree at path.
+func (s *SeekableDecoder) Slice(path ...interface{}) (io.Reader, error) {
+	if err := s.ensureIndex(); err != nil {
+		return nil, err
+	}
+	n, ok := s.lookup(path)
+	if !ok {
+		return nil, fmt.Errorf("jsonpath: SeekableDecoder.Slice: path %v not found", JsonPath(path))
+	}
+	if _, err := s.rs.Seek(n.Range.Start, io.SeekStart); err != nil {
+		return nil, err
+	}
+	return io.LimitReader(s.rs, n.Range.End-n.Range.Start), nil
+}
+
+// ForEach calls fn once for each element of the array at path, in order, with the SeekableDecoder
+// positioned to decode that element. Because the index already knows every element's offset, fn may
+// itself call SeekTo to jump elsewhere and back without disrupting the iteration.
+func (s *SeekableDecoder) ForEach(fn func(i int, d *SeekableDecoder) error, path ...interface{}) error {
+	if err := s.ensureIndex(); err != nil {
+		return err
+	}
+	n, ok := s.lookup(path)
+	if !ok {
+		return fmt.Errorf("jsonpath: SeekableDecoder.ForEach: path %v not found", JsonPath(path))
+	}
+	for i := 0; ; i++ {
+		c, ok := n.Children[i]
+		if !ok {
+			break
+		}
+		if err := s.seekDecoder(c.Range.Start); err != nil {
+			return err
+		}
+		if err := fn(i, s); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *SeekableDecoder) lookup(path []interface{}) (*indexNode, bool) {
+	n := s.index
+	for _, seg := range path {
+		if n.Children == nil {
+			return nil, false
+		}
+		c, ok := n.Children[seg]
+		if !ok {
+			return nil, false
+		}
+		n = c
+	}
+	return n, true
+}
+
+func (s *SeekableDecoder) seekDecoder(offset int64) error {
+	if _, err := s.rs.Seek(offset, io.SeekStart); err != nil {
+		return err
+	}
+	s.dec = NewDecoder(s.rs)
+	return nil
+}
+
+func (s *SeekableDecoder) ensureIndex() error {
+	if s.index != nil {
+		return nil
+	}
+	if _, err := s.rs.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	d := json.NewDecoder(s.rs)
+	var raw json.RawMessage
+	if err := d.Decode(&raw); err != nil {
+		return err
+	}
+	idx, err := buildIndex(raw)
+	if err != nil {
+		return err
+	}
+	idx.offsetBy(d.InputOffset() - int64(len(raw)))
+	s.index = idx
+	return nil
+}
+
+// buildIndex parses raw (the exact bytes of one JSON value, as produced by decoding into a
+// json.RawMessage) and recursively indexes its children, if any. Ranges are relative to the start of
+// raw; offsetBy shifts a whole (sub)tree once its absolute position in the document is known.
+//
+// Re-parsing each value from its own raw bytes, rather than tracking offsets as a single Decoder walks
+// the document, sidesteps having to account for the whitespace, colons and commas a streaming decoder
+// skips silently between tokens.
+func buildIndex(raw json.RawMessage) (*indexNode, error) {
+	n := &indexNode{Range: offsetRange{Start: 0, End: int64(len(raw))}}
+
+	trimmed := bytes.TrimSpace(raw)
+	if len(trimmed) == 0 || (trimmed[0] != '{' && trimmed[0] != '[') {
+		return n, nil
+	}
+
+	d := json.NewDecoder(bytes.NewReader(raw))
+	open, err := d.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch open {
+	case json.Delim('{'):
+		for d.More() {
+			keyTok, err := d.Token()
+			if err != nil {
+				return nil, err
+			}
+			child, err := decodeIndexedChild(d)
+			if err != nil {
+				return nil, err
+			}
+			n.addChild(keyTok.(string), child)
+		}
+	case json.Delim('['):
+		for i := 0; d.More(); i++ {
+			child, err := decodeIndexedChild(d)
+			if err != nil {
+				return nil, err
+			}
+			n.addChild(i, child)
+		}
+	}
+	if _, err := d.Token(); err != nil { // consume '}' or ']'
+		return nil, err
+	}
+	return n, nil
+}
+
+// decodeIndexedChild decodes the next value from d as a raw message, builds its index relative to its
+// own bytes, then shifts it to its absolute offset within the enclosing raw buffer.
+func decodeIndexedChild(d *json.Decoder) (*indexNode, error) {
+	var raw json.RawMessage
+	if err := d.Decode(&raw); err != nil {
+		return nil, err
+	}
+	child, err := buildIndex(raw)
+	if err != nil {
+		return nil, err
+	}
+	child.offsetBy(d.InputOffset() - int64(len(raw)))
+	return child, nil
+}
+
+func (n *indexNode) offsetBy(delta int64) {
+	n.Range.Start += delta
+	n.Range.End += delta
+	for _, c := range n.Children {
+		c.offsetBy(delta)
+	}
+}
+
+func (n *indexNode) addChild(key interface{}, child *indexNode) {
+	if n.Children == nil {
+		n.Children = make(map[interface{}]*indexNode)
+	}
+	n.Children[key] = child
+}
+
+// serialIndexNode is the on-disk form of an indexNode: encoding/json can't marshal a
+// map[interface{}]*indexNode directly, since object keys and array indices share one Go map, so object
+// and array children are serialized separately.
+type serialIndexNode struct {
+	Start  int64                       `json:"start"`
+	End    int64                       `json:"end"`
+	Object map[string]*serialIndexNode `json:"object,omitempty"`
+	Array  []*serialIndexNode          `json:"array,omitempty"`
+}
+
+// MarshalIndex serializes the SeekableDecoder's path index, building it first if necessary, so a tool
+// can pre-index a file once and reuse the index across runs via UnmarshalIndex.
+func (s *SeekableDecoder) MarshalIndex() ([]byte, error) {
+	if err := s.ensureIndex(); err != nil {
+		return nil, err
+	}
+	return json.Marshal(toSerialIndex(s.index))
+}
+
+// UnmarshalIndex loads a path index previously produced by MarshalIndex, replacing any index the
+// SeekableDecoder may already have built.
+func (s *SeekableDecoder) UnmarshalIndex(data []byte) error {
+	var sn serialIndexNode
+	if err := json.Unmarshal(data, &sn); err != nil {
+		return err
+	}
+	s.index = fromSerialIndex(&sn)
+	return nil
+}
+
+func toSerialIndex(n *indexNode) *serialIndexNode {
+	sn := &serialIndexNode{Start: n.Range.Start, End: n.Range.End}
+	for key := range n.Children {
+		switch key.(type) {
+		case string:
+			sn.Object = make(map[string]*serialIndexNode, len(n.Children))
+			for k, c := range n.Children {
+				sn.Object[k.(string)] = toSerialIndex(c)
+			}
+		case int:
+			max := -1
+			for k := range n.Children {
+				if i := k.(int); i > max {
+					max = i
+				}
+			}
+			sn.Array = make([]*serialIndexNode, max+1)
+			for k, c := range n.Children {
+				sn.Array[k.(int)] = toSerialIndex(c)
+			}
+		}
+		break
+	}
+	return sn
+}
+
+func fromSerialIndex(sn *serialIndexNode) *indexNode {
+	n := &indexNode{Range: offsetRange{Start: sn.Start, End: sn.End}}
+	switch {
+	case sn.Object != nil:
+		n.Children = make(map[interface{}]*indexNode, len(sn.Object))
+		for k, c := range sn.Object {
+			n.Children[k] = fromSerialIndex(c)
+		}
+	case sn.Array != nil:
+		n.Children = make(map[interface{}]*indexNode, len(sn.Array))
+		for i, c := range sn.Array {
+			if c != nil {
+				n.Children[i] = fromSerialIndex(c)
+			}
+		}
+	}
+	return n
+}
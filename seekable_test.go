@@ -0,0 +1,157 @@
+package jsonpath
+
+import (
+	"bytes"
+	"io"
+	"testing"
+)
+
+func TestSeekableDecoderSeekToAndDecode(t *testing.T) {
+	const doc = `{"a":[0,"s",12e4,{"b":0,"v":35}]}`
+	s := NewSeekableDecoder(bytes.NewReader([]byte(doc)))
+
+	ok, err := s.SeekTo("a", 3, "v")
+	if err != nil {
+		t.Fatalf("SeekTo: %v", err)
+	}
+	if !ok {
+		t.Fatal("SeekTo: path not found")
+	}
+
+	var v float64
+	if err := s.Decode(&v); err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	if v != 35 {
+		t.Fatalf("v = %v, want 35", v)
+	}
+}
+
+func TestSeekableDecoderSeeksBackward(t *testing.T) {
+	const doc = `{"a":1,"b":2,"c":3}`
+	s := NewSeekableDecoder(bytes.NewReader([]byte(doc)))
+
+	if ok, err := s.SeekTo("c"); err != nil || !ok {
+		t.Fatalf("SeekTo(c): ok=%v err=%v", ok, err)
+	}
+	var c int
+	if err := s.Decode(&c); err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+
+	// Jump backward to a path visited earlier in the document - something a forward-only Decoder
+	// can't do - and confirm the index still resolves it correctly.
+	if ok, err := s.SeekTo("a"); err != nil || !ok {
+		t.Fatalf("SeekTo(a): ok=%v err=%v", ok, err)
+	}
+	var a int
+	if err := s.Decode(&a); err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+
+	if a != 1 || c != 3 {
+		t.Fatalf("a=%v c=%v, want a=1 c=3", a, c)
+	}
+}
+
+func TestSeekableDecoderSeekToMissingPath(t *testing.T) {
+	s := NewSeekableDecoder(bytes.NewReader([]byte(`{"a":1}`)))
+	ok, err := s.SeekTo("missing")
+	if err != nil {
+		t.Fatalf("SeekTo: %v", err)
+	}
+	if ok {
+		t.Fatal("SeekTo: expected not found")
+	}
+}
+
+func TestSeekableDecoderDecodeBeforeSeekTo(t *testing.T) {
+	s := NewSeekableDecoder(bytes.NewReader([]byte(`{"a":1}`)))
+	var v int
+	if err := s.Decode(&v); err == nil {
+		t.Fatal("Decode: expected error before any SeekTo")
+	}
+}
+
+func TestSeekableDecoderSlice(t *testing.T) {
+	const doc = `{"a":{"b":1,"c":2},"d":3}`
+	s := NewSeekableDecoder(bytes.NewReader([]byte(doc)))
+
+	r, err := s.Slice("a")
+	if err != nil {
+		t.Fatalf("Slice: %v", err)
+	}
+	raw, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("ReadAll: %v", err)
+	}
+	if got, want := string(raw), `{"b":1,"c":2}`; got != want {
+		t.Fatalf("Slice bytes = %q, want %q", got, want)
+	}
+}
+
+func TestSeekableDecoderSliceMissingPath(t *testing.T) {
+	s := NewSeekableDecoder(bytes.NewReader([]byte(`{"a":1}`)))
+	if _, err := s.Slice("missing"); err == nil {
+		t.Fatal("Slice: expected error for missing path")
+	}
+}
+
+func TestSeekableDecoderForEach(t *testing.T) {
+	const doc = `{"items":[10,20,30]}`
+	s := NewSeekableDecoder(bytes.NewReader([]byte(doc)))
+
+	var got []int
+	err := s.ForEach(func(i int, d *SeekableDecoder) error {
+		var v int
+		if err := d.Decode(&v); err != nil {
+			return err
+		}
+		got = append(got, v)
+		return nil
+	}, "items")
+	if err != nil {
+		t.Fatalf("ForEach: %v", err)
+	}
+	if len(got) != 3 || got[0] != 10 || got[1] != 20 || got[2] != 30 {
+		t.Fatalf("got = %v, want [10 20 30]", got)
+	}
+}
+
+func TestSeekableDecoderForEachMissingPath(t *testing.T) {
+	s := NewSeekableDecoder(bytes.NewReader([]byte(`{"a":1}`)))
+	err := s.ForEach(func(i int, d *SeekableDecoder) error { return nil }, "missing")
+	if err == nil {
+		t.Fatal("ForEach: expected error for missing path")
+	}
+}
+
+func TestSeekableDecoderMarshalUnmarshalIndexRoundTrip(t *testing.T) {
+	const doc = `{"a":[1,2,{"b":3}]}`
+
+	s1 := NewSeekableDecoder(bytes.NewReader([]byte(doc)))
+	data, err := s1.MarshalIndex()
+	if err != nil {
+		t.Fatalf("MarshalIndex: %v", err)
+	}
+
+	s2 := NewSeekableDecoder(bytes.NewReader([]byte(doc)))
+	if err := s2.UnmarshalIndex(data); err != nil {
+		t.Fatalf("UnmarshalIndex: %v", err)
+	}
+
+	ok, err := s2.SeekTo("a", 2, "b")
+	if err != nil {
+		t.Fatalf("SeekTo: %v", err)
+	}
+	if !ok {
+		t.Fatal("SeekTo: path not found after UnmarshalIndex")
+	}
+	var b int
+	if err := s2.Decode(&b); err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	if b != 3 {
+		t.Fatalf("b = %v, want 3", b)
+	}
+}
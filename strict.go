@@ -0,0 +1,101 @@
+package jsonpath
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// DisallowDuplicateKeys enables or disables an opt-in strict mode in which Token and Decode report an
+// error if the same key appears twice in one JSON object, detected by tracking a per-object key set on
+// the top frame of the Decoder's path. This matches the "paranoid unmarshaling" practice used when
+// verifying container signatures or configs, where a duplicate key is often a sign of tampering.
+func (d *Decoder) DisallowDuplicateKeys(disallow bool) {
+	d.dupKeys = disallow
+}
+
+// fieldCheck is a path registered via DisallowUnknownFieldsAt, together with the JSON field names
+// allowed for the struct that will be decoded there.
+type fieldCheck struct {
+	path   JsonPath
+	fields map[string]bool
+}
+
+// DisallowUnknownFieldsAt enables an opt-in strict mode in which Token reports an error if an object at
+// path has a key that does not correspond to a JSON-tagged field of v, a pointer to the struct type that
+// will be decoded there. Unlike encoding/json's DisallowUnknownFields, this can be scoped to a single
+// path within a larger, otherwise-permissive document.
+func (d *Decoder) DisallowUnknownFieldsAt(path []interface{}, v interface{}) error {
+	fields, err := jsonFieldNames(v)
+	if err != nil {
+		return err
+	}
+	d.unknownFields = append(d.unknownFields, fieldCheck{path: JsonPath(path), fields: fields})
+	return nil
+}
+
+// jsonFieldNames returns the set of JSON field names encoding/json would populate on v, a pointer to a
+// struct (or a struct), honoring `json:"name"` tags and skipping unexported and "-" fields. Only the
+// struct's own fields are listed: an untagged embedded struct counts as one field named after its type
+// (or is skipped, if that type is unexported), and its fields are not promoted as encoding/json would
+// promote them.
+func jsonFieldNames(v interface{}) (map[string]bool, error) {
+	t := reflect.TypeOf(v)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("jsonpath: DisallowUnknownFieldsAt: %T is not a struct", v)
+	}
+
+	fields := make(map[string]bool)
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" { // unexported
+			continue
+		}
+		tag := f.Tag.Get("json")
+		name, _, _ := strings.Cut(tag, ",")
+		switch name {
+		case "-":
+			continue
+		case "":
+			name = f.Name
+		}
+		fields[name] = true
+	}
+	return fields, nil
+}
+
+// newKeySet returns a fresh per-object key set for the duplicate-key check, or nil if the check is
+// disabled, since entering and leaving an object always pushes and pops the stack regardless.
+func newKeySet(enabled bool) map[string]struct{} {
+	if !enabled {
+		return nil
+	}
+	return make(map[string]struct{})
+}
+
+// checkStrict runs whichever opt-in strict checks are enabled against the key just read at the
+// Decoder's current path (which already includes the key itself, as set by nameTop).
+func (d *Decoder) checkStrict(key string) error {
+	if d.dupKeys && len(d.keyStack) > 0 {
+		top := d.keyStack[len(d.keyStack)-1]
+		if top != nil {
+			if _, dup := top[key]; dup {
+				return fmt.Errorf("jsonpath: duplicate key %q", key)
+			}
+			top[key] = struct{}{}
+		}
+	}
+
+	if len(d.unknownFields) > 0 {
+		parent := d.path[:len(d.path)-1]
+		for _, fc := range d.unknownFields {
+			if fc.path.Equal(parent) && !fc.fields[key] {
+				return fmt.Errorf("jsonpath: unknown field %q at %s", key, parent.Pointer())
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package jsonpath
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestDisallowDuplicateKeysViaDecode(t *testing.T) {
+	d := NewDecoder(strings.NewReader(`{"a":1,"b":2,"a":3}`))
+	d.DisallowDuplicateKeys(true)
+
+	var out map[string]int
+	err := d.Decode(&out)
+	if err == nil {
+		t.Fatal("Decode: expected error for duplicate key, got nil")
+	}
+	if !strings.Contains(err.Error(), `duplicate key "a"`) {
+		t.Fatalf("Decode error = %v, want it to mention duplicate key %q", err, "a")
+	}
+}
+
+func TestDisallowDuplicateKeysViaDecodeAllowsUniqueKeys(t *testing.T) {
+	d := NewDecoder(strings.NewReader(`{"a":1,"b":2}`))
+	d.DisallowDuplicateKeys(true)
+
+	var out map[string]int
+	if err := d.Decode(&out); err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	if out["a"] != 1 || out["b"] != 2 {
+		t.Fatalf("out = %v, want a=1 b=2", out)
+	}
+}
+
+func TestDisallowUnknownFieldsAtViaDecode(t *testing.T) {
+	type pod struct {
+		Name string `json:"name"`
+	}
+
+	d := NewDecoder(strings.NewReader(`{"name":"x","extra":1}`))
+	if err := d.DisallowUnknownFieldsAt(nil, &pod{}); err != nil {
+		t.Fatalf("DisallowUnknownFieldsAt: %v", err)
+	}
+
+	var out pod
+	err := d.Decode(&out)
+	if err == nil {
+		t.Fatal("Decode: expected error for unknown field, got nil")
+	}
+	if !strings.Contains(err.Error(), `unknown field "extra"`) {
+		t.Fatalf("Decode error = %v, want it to mention unknown field %q", err, "extra")
+	}
+}
+
+func TestDisallowUnknownFieldsAtViaDecodeAllowsKnownFields(t *testing.T) {
+	type pod struct {
+		Name string `json:"name"`
+	}
+
+	d := NewDecoder(strings.NewReader(`{"name":"x"}`))
+	if err := d.DisallowUnknownFieldsAt(nil, &pod{}); err != nil {
+		t.Fatalf("DisallowUnknownFieldsAt: %v", err)
+	}
+
+	var out pod
+	if err := d.Decode(&out); err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	if out.Name != "x" {
+		t.Fatalf("out.Name = %q, want %q", out.Name, "x")
+	}
+}
+
+func TestDecodeErrorWrapsDuplicateKey(t *testing.T) {
+	d := NewDecoder(strings.NewReader(`{"a":1,"a":2}`))
+	d.DisallowDuplicateKeys(true)
+
+	var out map[string]int
+	err := d.Decode(&out)
+	var decErr *DecodeError
+	if !errors.As(err, &decErr) {
+		t.Fatalf("Decode error = %v (%T), want a *DecodeError", err, err)
+	}
+	if got, want := decErr.Pointer, JSONPointer("/a"); got != want {
+		t.Fatalf("decErr.Pointer = %q, want %q (the key where the duplicate was found)", got, want)
+	}
+}
+
+func TestJsonFieldNamesRejectsNonStruct(t *testing.T) {
+	d := NewDecoder(strings.NewReader(`{}`))
+	if err := d.DisallowUnknownFieldsAt(nil, 5); err == nil {
+		t.Fatal("DisallowUnknownFieldsAt: expected error for non-struct type")
+	}
+}